@@ -0,0 +1,42 @@
+// Copyright ©2017 The ev3go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hd44780
+
+import "github.com/ev3go/ev3/ev3dev/i2c"
+
+// pcf8574 drives an HD44780 through a PCF8574 quasi-bidirectional
+// I/O expander. The PCF8574 has no direction registers: writing a
+// byte simply drives its 8 output lines.
+type pcf8574 struct {
+	bus  *i2c.Bus
+	addr uint8
+}
+
+func (e *pcf8574) write(data byte) error {
+	return e.bus.SendByte(e.addr, data)
+}
+
+// MCP23008 register addresses used to configure the expander as an
+// all-output GPIO port.
+const (
+	mcp23008IODIR = 0x00
+	mcp23008GPIO  = 0x09
+)
+
+// mcp23008 drives an HD44780 through the 8 GPIOs of an MCP23008
+// I/O expander operated in native output mode.
+type mcp23008 struct {
+	bus  *i2c.Bus
+	addr uint8
+}
+
+// init configures all 8 GPIOs as outputs.
+func (e *mcp23008) init() error {
+	return e.bus.WriteToReg(e.addr, mcp23008IODIR, []byte{0x00})
+}
+
+func (e *mcp23008) write(data byte) error {
+	return e.bus.WriteToReg(e.addr, mcp23008GPIO, []byte{data})
+}
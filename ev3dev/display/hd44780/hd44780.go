@@ -0,0 +1,242 @@
+// Copyright ©2017 The ev3go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package hd44780 drives an HD44780-compatible character LCD
+// attached to an ev3dev sensor port configured for I2C, for use as
+// an external status display alongside (or instead of) the ev3's
+// built-in LCD.
+//
+// Two common wirings are supported: the PCF8574 "I2C backpack",
+// where RS, E, the backlight switch and the four high data lines
+// D4-D7 are wired to P0-P7 of the expander, and native 4-bit
+// parallel mode driven through the 8 GPIOs of an MCP23008 I/O
+// expander.
+package hd44780
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ev3go/ev3/ev3dev/i2c"
+)
+
+// HD44780 instruction and entry/display/function-set flags, as
+// given in the HD44780 datasheet.
+const (
+	cmdClearDisplay = 0x01
+	cmdReturnHome   = 0x02
+	cmdEntryModeSet = 0x04
+	cmdDisplayCtl   = 0x08
+	cmdCursorShift  = 0x10
+	cmdFunctionSet  = 0x20
+	cmdSetCGRAMAddr = 0x40
+	cmdSetDDRAMAddr = 0x80
+
+	entryLeft     = 0x02
+	displayOn     = 0x04
+	display4Bit   = 0x00
+	display2Line  = 0x08
+	display5x8Dot = 0x00
+)
+
+// Instruction timing from the HD44780 datasheet: most instructions
+// need about 37-43us to execute before the next one can be issued,
+// but Clear Display and Return Home need much longer, up to 1.52ms.
+// nibbleSettle is applied conservatively after every nibble write so
+// that it also covers the per-nibble delays required during the
+// 4-bit initialization sequence.
+const (
+	nibbleSettle = 50 * time.Microsecond
+	clearSettle  = 2 * time.Millisecond
+)
+
+// Pin bit assignments shared by both supported wirings: RS is P0,
+// E is P2, the backlight switch is P3, and the four data lines
+// D4-D7 occupy the top nibble.
+const (
+	rsBit     = 1 << 0
+	enBit     = 1 << 2
+	blBit     = 1 << 3
+	dataShift = 4
+)
+
+// rowOffsets gives the DDRAM address of the first column of each of
+// the display's up to four rows.
+var rowOffsets = [4]byte{0x00, 0x40, 0x14, 0x54}
+
+// expander abstracts over the I2C GPIO expander used to drive the
+// HD44780's control and data lines.
+type expander interface {
+	// write outputs the given byte to the expander's GPIOs,
+	// where bit assignments match the wiring documented on
+	// New: RS, E, BL, then D4-D7.
+	write(data byte) error
+}
+
+// Display is a character LCD driven over I2C through a GPIO
+// expander. It is not safe for concurrent use.
+type Display struct {
+	exp  expander
+	cols int
+	rows int
+
+	backlight byte
+}
+
+// New returns a Display for a cols x rows HD44780 character LCD
+// wired through the very common PCF8574 I2C backpack, where RS is
+// P0, E is P2, the backlight switch is P3, and D4-D7 are P4-P7.
+func New(bus *i2c.Bus, addr uint8, cols, rows int) (*Display, error) {
+	return newDisplay(&pcf8574{bus: bus, addr: addr}, cols, rows)
+}
+
+// NewMCP23008 returns a Display for a cols x rows HD44780 character
+// LCD wired through the 8 GPIOs of an MCP23008 in native 4-bit
+// parallel mode, using the same RS/E/BL/D4-D7 pin assignment as the
+// PCF8574 wiring used by New.
+func NewMCP23008(bus *i2c.Bus, addr uint8, cols, rows int) (*Display, error) {
+	exp := &mcp23008{bus: bus, addr: addr}
+	if err := exp.init(); err != nil {
+		return nil, err
+	}
+	return newDisplay(exp, cols, rows)
+}
+
+func newDisplay(exp expander, cols, rows int) (*Display, error) {
+	if rows < 1 || rows > len(rowOffsets) {
+		return nil, fmt.Errorf("hd44780: unsupported row count: %d", rows)
+	}
+
+	d := &Display{exp: exp, cols: cols, rows: rows, backlight: blBit}
+
+	// The standard HD44780 power-on sequence for 4-bit mode.
+	for _, n := range []byte{0x03, 0x03, 0x03, 0x02} {
+		if err := d.nibble(n, false); err != nil {
+			return nil, err
+		}
+	}
+	if err := d.command(cmdFunctionSet | display4Bit | display2Line | display5x8Dot); err != nil {
+		return nil, err
+	}
+	if err := d.command(cmdDisplayCtl | displayOn); err != nil {
+		return nil, err
+	}
+	if err := d.Clear(); err != nil {
+		return nil, err
+	}
+	if err := d.command(cmdEntryModeSet | entryLeft); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// Clear clears the display and returns the cursor to the origin.
+func (d *Display) Clear() error {
+	return d.command(cmdClearDisplay)
+}
+
+// Home returns the cursor to the origin without clearing the
+// display.
+func (d *Display) Home() error {
+	return d.command(cmdReturnHome)
+}
+
+// SetCursor moves the cursor to the given column and row, both
+// zero-indexed.
+func (d *Display) SetCursor(col, row int) error {
+	if row < 0 || row >= d.rows {
+		return fmt.Errorf("hd44780: row out of range: %d", row)
+	}
+	if col < 0 || col >= d.cols {
+		return fmt.Errorf("hd44780: column out of range: %d", col)
+	}
+	return d.command(cmdSetDDRAMAddr | (rowOffsets[row] + byte(col)))
+}
+
+// CreateChar stores an 8-byte custom glyph pattern in one of the
+// eight CGRAM slots (0-7) so that it can subsequently be written as
+// the byte value index.
+func (d *Display) CreateChar(index uint8, pattern [8]byte) error {
+	if index > 7 {
+		return fmt.Errorf("hd44780: glyph index out of range: %d", index)
+	}
+	if err := d.command(cmdSetCGRAMAddr | (index << 3)); err != nil {
+		return err
+	}
+	for _, row := range pattern {
+		if err := d.write(row & 0x1f); err != nil {
+			return err
+		}
+	}
+	return d.command(cmdSetDDRAMAddr)
+}
+
+// Write writes p to the display at the current cursor position,
+// satisfying io.Writer. It does not wrap at the end of a line.
+func (d *Display) Write(p []byte) (int, error) {
+	for i, c := range p {
+		if err := d.write(c); err != nil {
+			return i, err
+		}
+	}
+	return len(p), nil
+}
+
+// WriteString writes s to the display at the current cursor
+// position.
+func (d *Display) WriteString(s string) error {
+	_, err := d.Write([]byte(s))
+	return err
+}
+
+// BacklightOn switches the display's backlight on.
+func (d *Display) BacklightOn() error {
+	d.backlight = blBit
+	return d.exp.write(d.backlight)
+}
+
+// BacklightOff switches the display's backlight off.
+func (d *Display) BacklightOff() error {
+	d.backlight = 0
+	return d.exp.write(d.backlight)
+}
+
+func (d *Display) command(cmd byte) error {
+	if err := d.send(cmd, false); err != nil {
+		return err
+	}
+	if cmd == cmdClearDisplay || cmd == cmdReturnHome {
+		time.Sleep(clearSettle)
+	}
+	return nil
+}
+
+func (d *Display) write(data byte) error {
+	return d.send(data, true)
+}
+
+func (d *Display) send(value byte, rs bool) error {
+	if err := d.nibble(value>>4, rs); err != nil {
+		return err
+	}
+	return d.nibble(value&0x0f, rs)
+}
+
+func (d *Display) nibble(n byte, rs bool) error {
+	out := d.backlight | n<<dataShift
+	if rs {
+		out |= rsBit
+	}
+	if err := d.exp.write(out); err != nil {
+		return err
+	}
+	if err := d.exp.write(out | enBit); err != nil {
+		return err
+	}
+	if err := d.exp.write(out); err != nil {
+		return err
+	}
+	time.Sleep(nibbleSettle)
+	return nil
+}
@@ -0,0 +1,229 @@
+// Copyright ©2017 The ev3go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hd44780
+
+import (
+	"errors"
+	"testing"
+)
+
+// fakeExpander records every byte written to it, standing in for a
+// real PCF8574/MCP23008 I2C expander.
+type fakeExpander struct {
+	writes []byte
+
+	failAt int // index of the write call that should fail, or -1
+	err    error
+}
+
+func (e *fakeExpander) write(data byte) error {
+	if e.failAt >= 0 && len(e.writes) == e.failAt {
+		return e.err
+	}
+	e.writes = append(e.writes, data)
+	return nil
+}
+
+// nibble is a single decoded nibble write, extracted from the three
+// raw expander writes (set up, pulse E, release) that nibble()
+// issues for it.
+type nibble struct {
+	rs  bool
+	val byte
+}
+
+func nibblesFrom(t *testing.T, writes []byte) []nibble {
+	t.Helper()
+	if len(writes)%3 != 0 {
+		t.Fatalf("expander writes not a multiple of 3 per nibble: got %d", len(writes))
+	}
+	var out []nibble
+	for i := 0; i+2 < len(writes); i += 3 {
+		set, pulse, release := writes[i], writes[i+1], writes[i+2]
+		if pulse != set|enBit || release != set {
+			t.Fatalf("malformed nibble write sequence at %d: %#02x %#02x %#02x", i, set, pulse, release)
+		}
+		out = append(out, nibble{rs: set&rsBit != 0, val: (set >> dataShift) & 0x0f})
+	}
+	return out
+}
+
+// bytesFrom pairs up nibbles into the command/data bytes sent by
+// send: high nibble first, then low nibble.
+func bytesFrom(t *testing.T, nibbles []nibble) []byte {
+	t.Helper()
+	if len(nibbles)%2 != 0 {
+		t.Fatalf("nibbles not a multiple of 2 per byte: got %d", len(nibbles))
+	}
+	var out []byte
+	for i := 0; i+1 < len(nibbles); i += 2 {
+		hi, lo := nibbles[i], nibbles[i+1]
+		if hi.rs != lo.rs {
+			t.Fatalf("nibble pair at %d has mismatched RS", i)
+		}
+		out = append(out, hi.val<<4|lo.val)
+	}
+	return out
+}
+
+func TestNewDisplayInitSequence(t *testing.T) {
+	exp := &fakeExpander{failAt: -1}
+	if _, err := newDisplay(exp, 16, 2); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	nibbles := nibblesFrom(t, exp.writes)
+	wantInit := []byte{0x03, 0x03, 0x03, 0x02}
+	if len(nibbles) < len(wantInit) {
+		t.Fatalf("too few nibbles written: got %d, want at least %d", len(nibbles), len(wantInit))
+	}
+	for i, want := range wantInit {
+		if nibbles[i].val != want || nibbles[i].rs {
+			t.Errorf("init nibble %d: got val=%#x rs=%v, want val=%#x rs=false", i, nibbles[i].val, nibbles[i].rs, want)
+		}
+	}
+
+	gotCmds := bytesFrom(t, nibbles[len(wantInit):])
+	wantCmds := []byte{
+		cmdFunctionSet | display4Bit | display2Line | display5x8Dot,
+		cmdDisplayCtl | displayOn,
+		cmdClearDisplay,
+		cmdEntryModeSet | entryLeft,
+	}
+	if len(gotCmds) != len(wantCmds) {
+		t.Fatalf("unexpected number of commands sent: got %d, want %d", len(gotCmds), len(wantCmds))
+	}
+	for i, want := range wantCmds {
+		if gotCmds[i] != want {
+			t.Errorf("init command %d: got %#02x, want %#02x", i, gotCmds[i], want)
+		}
+	}
+}
+
+func TestNewDisplayInvalidRows(t *testing.T) {
+	exp := &fakeExpander{failAt: -1}
+	if _, err := newDisplay(exp, 16, 0); err == nil {
+		t.Error("expected an error for a display with zero rows")
+	}
+	if _, err := newDisplay(exp, 16, len(rowOffsets)+1); err == nil {
+		t.Error("expected an error for a display with too many rows")
+	}
+	if len(exp.writes) != 0 {
+		t.Errorf("expected no expander writes for a rejected row count, got %d", len(exp.writes))
+	}
+}
+
+func TestDisplayWriteSetsRS(t *testing.T) {
+	exp := &fakeExpander{failAt: -1}
+	d, err := newDisplay(exp, 16, 2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	exp.writes = nil
+
+	if _, err := d.Write([]byte("A")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	nibbles := nibblesFrom(t, exp.writes)
+	got := bytesFrom(t, nibbles)
+	if len(got) != 1 || got[0] != 'A' {
+		t.Fatalf("got bytes %v, want ['A']", got)
+	}
+	for _, n := range nibbles {
+		if !n.rs {
+			t.Error("expected RS to be set for a data write")
+		}
+	}
+}
+
+func TestDisplaySetCursorRange(t *testing.T) {
+	exp := &fakeExpander{failAt: -1}
+	d, err := newDisplay(exp, 16, 2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err := d.SetCursor(0, 2); err == nil {
+		t.Error("expected an error for a row beyond the display's row count")
+	}
+	if err := d.SetCursor(16, 0); err == nil {
+		t.Error("expected an error for a column beyond the display's column count")
+	}
+	if err := d.SetCursor(-1, 0); err == nil {
+		t.Error("expected an error for a negative column")
+	}
+	if err := d.SetCursor(0, 1); err != nil {
+		t.Errorf("unexpected error for an in-range cursor move: %v", err)
+	}
+}
+
+func TestDisplayCreateChar(t *testing.T) {
+	exp := &fakeExpander{failAt: -1}
+	d, err := newDisplay(exp, 16, 2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	exp.writes = nil
+
+	pattern := [8]byte{0x1f, 0x00, 0xff, 0x15, 0x0a, 0x15, 0x1f, 0x00}
+	if err := d.CreateChar(3, pattern); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	nibbles := nibblesFrom(t, exp.writes)
+	got := bytesFrom(t, nibbles)
+	if len(got) != 10 {
+		t.Fatalf("got %d bytes sent, want 10 (1 address command + 8 pattern rows + 1 restore command)", len(got))
+	}
+
+	if got[0] != cmdSetCGRAMAddr|(3<<3) || nibbles[0].rs || nibbles[1].rs {
+		t.Errorf("got CGRAM address command %#02x (rs=%v), want %#02x (rs=false)", got[0], nibbles[0].rs, cmdSetCGRAMAddr|(3<<3))
+	}
+
+	for i, row := range pattern {
+		want := row & 0x1f
+		if got[1+i] != want {
+			t.Errorf("pattern row %d: got %#02x, want %#02x (masked to 5 bits)", i, got[1+i], want)
+		}
+		lo, hi := 2*(1+i), 2*(1+i)+1
+		if !nibbles[lo].rs || !nibbles[hi].rs {
+			t.Errorf("pattern row %d: expected RS to be set for a data write", i)
+		}
+	}
+
+	last := got[len(got)-1]
+	if last != cmdSetDDRAMAddr {
+		t.Errorf("got trailing command %#02x, want cmdSetDDRAMAddr (%#02x)", last, cmdSetDDRAMAddr)
+	}
+	if nibbles[len(nibbles)-2].rs || nibbles[len(nibbles)-1].rs {
+		t.Error("expected RS to be clear for the trailing restore command")
+	}
+}
+
+func TestDisplayCreateCharIndexOutOfRange(t *testing.T) {
+	exp := &fakeExpander{failAt: -1}
+	d, err := newDisplay(exp, 16, 2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	exp.writes = nil
+
+	if err := d.CreateChar(8, [8]byte{}); err == nil {
+		t.Error("expected an error for a glyph index beyond the 8 CGRAM slots")
+	}
+	if len(exp.writes) != 0 {
+		t.Errorf("expected no expander writes for a rejected glyph index, got %d", len(exp.writes))
+	}
+}
+
+func TestDisplayPropagatesExpanderError(t *testing.T) {
+	wantErr := errors.New("hd44780: fake expander failure")
+	exp := &fakeExpander{failAt: 0, err: wantErr}
+
+	if _, err := newDisplay(exp, 16, 2); err != wantErr {
+		t.Errorf("expected newDisplay to propagate expander write error, got %v", err)
+	}
+}
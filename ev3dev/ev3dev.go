@@ -24,13 +24,12 @@ const (
 	sensorPrefix = "sensor"
 )
 
-const (
-	// LEDPath is the path to the ev3 LED file system.
-	LEDPath = "/sys/class/leds"
-
-	// LegoPortPath is the path to the ev3 lego-port file system.
-	LegoPortPath = "/sys/class/lego-port"
+// LEDPath is the path to the ev3 LED file system. It is a var
+// rather than a const so that tests can point it at a fake LED
+// sysfs tree.
+var LEDPath = "/sys/class/leds"
 
+const (
 	// SensorPath is the path to the ev3 lego-sensor file system.
 	SensorPath = "/sys/class/lego-sensor"
 
@@ -44,6 +43,11 @@ const (
 	DCMotorPath = "/sys/class/dc-motor"
 )
 
+// LegoPortPath is the path to the ev3 lego-port file system. It is
+// a var rather than a const so that tests can point it at a fake
+// lego-port sysfs tree.
+var LegoPortPath = "/sys/class/lego-port"
+
 // These are the subsystem path definitions for all device classes.
 const (
 	address                   = "address"
@@ -107,6 +111,13 @@ const (
 	value                     = "value"
 )
 
+// Port identifies a lego-port sysfs device by its directory name
+// under LegoPortPath, such as "port0".
+type Port string
+
+// String satisfies the fmt.Stringer interface.
+func (p Port) String() string { return string(p) }
+
 // Polarity represent motor polarity states.
 type Polarity string
 
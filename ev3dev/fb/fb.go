@@ -0,0 +1,75 @@
+// Copyright ©2016 The ev3go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package fb provides draw.Image pixel formats for raw framebuffer
+// devices, such as the ev3's /dev/fb0.
+package fb
+
+import (
+	"image"
+	"image/color"
+)
+
+// XRGB is a draw.Image backed by 32-bit-per-pixel XRGB8888 pixel
+// data, the format used by the ev3's /dev/fb0 framebuffer.
+type XRGB struct {
+	// Pix holds the image's pixels, in XRGB8888 order. The pixel
+	// at (x, y) starts at Pix[(y-Rect.Min.Y)*Stride+(x-Rect.Min.X)*4].
+	Pix []byte
+
+	// Stride is the Pix stride, in bytes, between vertically
+	// adjacent pixels.
+	Stride int
+
+	// Rect is the image's bounds.
+	Rect image.Rectangle
+}
+
+// NewXRGBWith returns a new XRGB image with the given width, height
+// and stride, backed by pix. It panics if pix is too small to hold
+// an image of that size.
+func NewXRGBWith(pix []byte, w, h, stride int) *XRGB {
+	if len(pix) < stride*h {
+		panic("fb: pixel buffer too small for image bounds")
+	}
+	return &XRGB{Pix: pix, Stride: stride, Rect: image.Rect(0, 0, w, h)}
+}
+
+// ColorModel satisfies the image.Image interface.
+func (p *XRGB) ColorModel() color.Model { return color.RGBAModel }
+
+// Bounds satisfies the image.Image interface.
+func (p *XRGB) Bounds() image.Rectangle { return p.Rect }
+
+// PixOffset returns the index of the first element of Pix that
+// corresponds to the pixel at (x, y).
+func (p *XRGB) PixOffset(x, y int) int {
+	return (y-p.Rect.Min.Y)*p.Stride + (x-p.Rect.Min.X)*4
+}
+
+// At satisfies the image.Image interface.
+func (p *XRGB) At(x, y int) color.Color {
+	return p.RGBAAt(x, y)
+}
+
+// RGBAAt returns the color.RGBA value at (x, y).
+func (p *XRGB) RGBAAt(x, y int) color.RGBA {
+	if !(image.Point{X: x, Y: y}.In(p.Rect)) {
+		return color.RGBA{}
+	}
+	i := p.PixOffset(x, y)
+	return color.RGBA{R: p.Pix[i+2], G: p.Pix[i+1], B: p.Pix[i], A: 0xff}
+}
+
+// Set satisfies the draw.Image interface.
+func (p *XRGB) Set(x, y int, c color.Color) {
+	if !(image.Point{X: x, Y: y}.In(p.Rect)) {
+		return
+	}
+	r, g, b, _ := c.RGBA()
+	i := p.PixOffset(x, y)
+	p.Pix[i] = byte(b >> 8)
+	p.Pix[i+1] = byte(g >> 8)
+	p.Pix[i+2] = byte(r >> 8)
+}
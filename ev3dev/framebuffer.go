@@ -0,0 +1,67 @@
+// Copyright ©2016 The ev3go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ev3dev
+
+import (
+	"fmt"
+	"image/color"
+	"image/draw"
+	"os"
+	"sync"
+
+	"github.com/ev3go/ev3/ev3dev/fb"
+)
+
+// FrameBuffer is a draw.Image backed by a fixed-size pixel buffer
+// that can be pushed out to a framebuffer device, such as the ev3's
+// /dev/fb0, with Flush.
+type FrameBuffer struct {
+	draw.Image
+
+	path string
+	pix  []byte
+
+	mu sync.Mutex
+}
+
+// NewFrameBuffer returns a FrameBuffer of the given width and height
+// in pixels and stride in bytes backed by an image constructed by newImage,
+// that Flushes its pixels to the framebuffer device at path.
+func NewFrameBuffer(path string, newImage func(pix []byte, w, h, stride int) *fb.XRGB, w, h, stride int) *FrameBuffer {
+	pix := make([]byte, stride*h)
+	return &FrameBuffer{Image: newImage(pix, w, h, stride), path: path, pix: pix}
+}
+
+// At satisfies the image.Image interface.
+func (f *FrameBuffer) At(x, y int) color.Color {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.Image.At(x, y)
+}
+
+// Set satisfies the draw.Image interface.
+func (f *FrameBuffer) Set(x, y int, c color.Color) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.Image.Set(x, y, c)
+}
+
+// Flush writes the FrameBuffer's current pixels to its framebuffer
+// device.
+func (f *FrameBuffer) Flush() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	file, err := os.OpenFile(f.path, os.O_WRONLY, 0)
+	if err != nil {
+		return fmt.Errorf("ev3dev: failed to open framebuffer device: %v", err)
+	}
+	defer file.Close()
+
+	if _, err := file.Write(f.pix); err != nil {
+		return fmt.Errorf("ev3dev: failed to write framebuffer device: %v", err)
+	}
+	return nil
+}
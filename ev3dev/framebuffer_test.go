@@ -0,0 +1,44 @@
+// Copyright ©2016 The ev3go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ev3dev
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ev3go/ev3/ev3dev/fb"
+)
+
+func TestFrameBufferFlush(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "fb0")
+	if err := os.WriteFile(path, nil, 0644); err != nil {
+		t.Fatalf("failed to create fake framebuffer device: %v", err)
+	}
+
+	const w, h, stride = 2, 2, 2
+	fbuf := NewFrameBuffer(path, fb.NewXRGBWith, w, h, stride)
+	for i := range fbuf.pix {
+		fbuf.pix[i] = byte(i + 1)
+	}
+
+	if err := fbuf.Flush(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read flushed framebuffer device: %v", err)
+	}
+	if len(got) != len(fbuf.pix) {
+		t.Fatalf("got %d bytes flushed, want %d", len(got), len(fbuf.pix))
+	}
+	for i, b := range got {
+		if b != fbuf.pix[i] {
+			t.Errorf("byte %d: got %d, want %d", i, b, fbuf.pix[i])
+		}
+	}
+}
@@ -0,0 +1,47 @@
+// Copyright ©2016 The ev3go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ev3dev
+
+import "sync"
+
+// Host holds the device handles exposed by an ev3dev-compatible
+// board. Fields that have no corresponding hardware on a given
+// board are left nil.
+type Host struct {
+	// LED holds the LED handles available on the host, keyed
+	// by a short logical name such as "green-left".
+	LED map[string]*LED
+
+	// LCD is the framebuffer-backed display for the host.
+	LCD *FrameBuffer
+
+	// Speaker is the tone/beeper handle for the host.
+	Speaker *Speaker
+}
+
+var (
+	mu    sync.Mutex
+	hosts = make(map[string]Host)
+)
+
+// RegisterHost registers the device handles for a board under the
+// given name so that they can be retrieved with HostByName, or
+// located automatically by host.Detect. RegisterHost is intended
+// to be called from the init function of a host-specific package
+// such as github.com/ev3go/ev3/host/ev3.
+func RegisterHost(name string, h Host) {
+	mu.Lock()
+	defer mu.Unlock()
+	hosts[name] = h
+}
+
+// HostByName returns the Host registered under the given name and
+// whether a host was found with that name.
+func HostByName(name string) (Host, bool) {
+	mu.Lock()
+	defer mu.Unlock()
+	h, ok := hosts[name]
+	return h, ok
+}
@@ -0,0 +1,25 @@
+// Copyright ©2017 The ev3go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ev3dev
+
+import "testing"
+
+func TestRegisterAndLookupHost(t *testing.T) {
+	const name = "test-host"
+	speaker := NewSpeaker("")
+	RegisterHost(name, Host{Speaker: speaker})
+
+	got, ok := HostByName(name)
+	if !ok {
+		t.Fatalf("HostByName(%q): host not found", name)
+	}
+	if got.Speaker != speaker {
+		t.Errorf("HostByName(%q): got Speaker %p, want %p", name, got.Speaker, speaker)
+	}
+
+	if _, ok := HostByName("no-such-host"); ok {
+		t.Error("HostByName: unexpectedly found a host that was never registered")
+	}
+}
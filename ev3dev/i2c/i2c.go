@@ -0,0 +1,210 @@
+// Copyright ©2017 The ev3go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package i2c provides access to I2C peripherals attached to an
+// ev3dev Input port that has been configured for i2c-thru or
+// other-i2c mode. This allows devices that have no lego-sensor
+// kernel driver, such as the BMP180, MPU6050, PCA9685 or MCP4725,
+// to be driven directly.
+package i2c
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"unsafe"
+
+	"github.com/ev3go/ev3/ev3dev"
+)
+
+// ioctl request numbers for the Linux i2c-dev driver. See
+// linux/i2c-dev.h and linux/i2c.h.
+const (
+	i2cSlave = 0x0703
+	i2cSMBus = 0x0720
+)
+
+// SMBus transaction types, from linux/i2c.h.
+const (
+	smbusRead  = 1
+	smbusWrite = 0
+
+	smbusByte         = 1
+	smbusByteData     = 2
+	smbusWordData     = 3
+	smbusBlockData    = 5
+	smbusI2CBlockData = 8
+)
+
+const i2cSMBusBlockMax = 32
+
+// smbusIoctlData mirrors struct i2c_smbus_ioctl_data.
+type smbusIoctlData struct {
+	readWrite uint8
+	command   uint8
+	size      uint32
+	data      uintptr
+}
+
+// Bus is a handle to an I2C bus exposed by an ev3dev Input port.
+// It is safe for concurrent use.
+type Bus struct {
+	mu   sync.Mutex
+	f    *os.File
+	addr byte
+}
+
+// Open opens the I2C bus attached to the given sensor port. The
+// port must already be set to the i2c-thru or other-i2c mode so
+// that ev3dev exposes a /dev/i2c-N character device for it.
+func Open(port ev3dev.Port) (*Bus, error) {
+	n, err := busIndexFor(port)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := os.OpenFile(fmt.Sprintf("/dev/i2c-%d", n), os.O_RDWR, 0)
+	if err != nil {
+		return nil, fmt.Errorf("i2c: failed to open /dev/i2c-%d: %v", n, err)
+	}
+	return &Bus{f: f}, nil
+}
+
+// busIndexFor returns the /dev/i2c-N index for the given sensor
+// port by inspecting the i2c attribute of its lego-port sysfs
+// directory, which is either a symlink to the i2c-dev device or a
+// plain file naming it.
+func busIndexFor(port ev3dev.Port) (int, error) {
+	path := filepath.Join(ev3dev.LegoPortPath, port.String(), "i2c")
+
+	if target, err := os.Readlink(path); err == nil {
+		return parseBusIndex(filepath.Base(target))
+	}
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return -1, fmt.Errorf("i2c: failed to find i2c-dev for port %s: %v", port, err)
+	}
+	return parseBusIndex(strings.TrimSpace(string(b)))
+}
+
+func parseBusIndex(name string) (int, error) {
+	name = strings.TrimPrefix(strings.TrimSpace(name), "i2c-")
+	n, err := strconv.Atoi(name)
+	if err != nil {
+		return -1, fmt.Errorf("i2c: could not parse bus index from %q: %v", name, err)
+	}
+	return n, nil
+}
+
+// Close closes the bus.
+func (b *Bus) Close() error {
+	return b.f.Close()
+}
+
+func (b *Bus) setSlave(addr byte) error {
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, b.f.Fd(), i2cSlave, uintptr(addr))
+	if errno != 0 {
+		return fmt.Errorf("i2c: failed to set slave address %#x: %v", addr, errno)
+	}
+	b.addr = addr
+	return nil
+}
+
+func (b *Bus) smbusAccess(addr byte, readWrite uint8, command uint8, size uint32, data uintptr) error {
+	if err := b.setSlave(addr); err != nil {
+		return err
+	}
+	args := smbusIoctlData{
+		readWrite: readWrite,
+		command:   command,
+		size:      size,
+		data:      data,
+	}
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, b.f.Fd(), i2cSMBus, uintptr(unsafe.Pointer(&args)))
+	if errno != 0 {
+		return fmt.Errorf("i2c: smbus access to %#x failed: %v", addr, errno)
+	}
+	return nil
+}
+
+// ReceiveByte reads a single byte from the device at addr using an
+// SMBus receive-byte transaction. It is not named ReadByte to avoid
+// colliding with the io.ByteReader method signature, which go vet's
+// stdmethods check expects of any ReadByte method.
+func (b *Bus) ReceiveByte(addr byte) (byte, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var data [2]byte
+	err := b.smbusAccess(addr, smbusRead, 0, smbusByte, uintptr(unsafe.Pointer(&data)))
+	return data[0], err
+}
+
+// SendByte writes a single byte to the device at addr using an
+// SMBus send-byte transaction. It is not named WriteByte to avoid
+// colliding with the io.ByteWriter method signature, which go vet's
+// stdmethods check expects of any WriteByte method.
+func (b *Bus) SendByte(addr, value byte) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.smbusAccess(addr, smbusWrite, value, smbusByte, 0)
+}
+
+// ReadFromReg reads len(buf) bytes from register reg of the device
+// at addr into buf using repeated I2C block-data transactions.
+func (b *Bus) ReadFromReg(addr, reg byte, buf []byte) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for len(buf) > 0 {
+		n := len(buf)
+		if n > i2cSMBusBlockMax {
+			n = i2cSMBusBlockMax
+		}
+
+		var data [i2cSMBusBlockMax + 1]byte
+		data[0] = byte(n)
+		err := b.smbusAccess(addr, smbusRead, reg, smbusI2CBlockData, uintptr(unsafe.Pointer(&data)))
+		if err != nil {
+			return fmt.Errorf("i2c: failed to read register %#x of %#x: %v", reg, addr, err)
+		}
+		copy(buf[:n], data[1:1+n])
+		buf = buf[n:]
+		reg += byte(n)
+	}
+	return nil
+}
+
+// WriteToReg writes buf to register reg of the device at addr
+// using an I2C block-data transaction. buf must not be longer
+// than 32 bytes.
+func (b *Bus) WriteToReg(addr, reg byte, buf []byte) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if len(buf) > i2cSMBusBlockMax {
+		return fmt.Errorf("i2c: write to register %#x of %#x too long: %d bytes", reg, addr, len(buf))
+	}
+
+	var data [i2cSMBusBlockMax + 1]byte
+	data[0] = byte(len(buf))
+	copy(data[1:], buf)
+	return b.smbusAccess(addr, smbusWrite, reg, smbusI2CBlockData, uintptr(unsafe.Pointer(&data)))
+}
+
+// WriteWord writes a 16-bit little-endian word to register reg of
+// the device at addr using an SMBus write-word transaction.
+func (b *Bus) WriteWord(addr, reg byte, word uint16) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	data := [2]byte{byte(word), byte(word >> 8)}
+	return b.smbusAccess(addr, smbusWrite, reg, smbusWordData, uintptr(unsafe.Pointer(&data)))
+}
@@ -0,0 +1,103 @@
+// Copyright ©2017 The ev3go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package i2c
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ev3go/ev3/ev3dev"
+)
+
+// withFakeLegoPort points ev3dev.LegoPortPath at a temporary
+// directory containing a single port subdirectory, and returns a
+// function that restores it. makeI2C is called with the port
+// directory so the test can set up either a symlinked or plain-file
+// i2c attribute.
+func withFakeLegoPort(t *testing.T, port string, makeI2C func(dir string)) func() {
+	t.Helper()
+
+	dir := t.TempDir()
+	portDir := filepath.Join(dir, port)
+	if err := os.Mkdir(portDir, 0755); err != nil {
+		t.Fatalf("failed to create fake port directory: %v", err)
+	}
+	makeI2C(portDir)
+
+	old := ev3dev.LegoPortPath
+	ev3dev.LegoPortPath = dir
+	return func() { ev3dev.LegoPortPath = old }
+}
+
+func TestBusIndexForSymlink(t *testing.T) {
+	const port = "port0"
+	defer withFakeLegoPort(t, port, func(portDir string) {
+		if err := os.Symlink("i2c-3", filepath.Join(portDir, "i2c")); err != nil {
+			t.Fatalf("failed to create fake i2c symlink: %v", err)
+		}
+	})()
+
+	n, err := busIndexFor(ev3dev.Port(port))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if n != 3 {
+		t.Errorf("got bus index %d, want 3", n)
+	}
+}
+
+func TestBusIndexForPlainFile(t *testing.T) {
+	const port = "port0"
+	defer withFakeLegoPort(t, port, func(portDir string) {
+		if err := os.WriteFile(filepath.Join(portDir, "i2c"), []byte("i2c-5\n"), 0644); err != nil {
+			t.Fatalf("failed to write fake i2c attribute: %v", err)
+		}
+	})()
+
+	n, err := busIndexFor(ev3dev.Port(port))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if n != 5 {
+		t.Errorf("got bus index %d, want 5", n)
+	}
+}
+
+func TestBusIndexForMissing(t *testing.T) {
+	const port = "port0"
+	defer withFakeLegoPort(t, port, func(portDir string) {})()
+
+	if _, err := busIndexFor(ev3dev.Port(port)); err == nil {
+		t.Error("expected an error for a port with no i2c attribute")
+	}
+}
+
+func TestParseBusIndex(t *testing.T) {
+	for _, test := range []struct {
+		name    string
+		want    int
+		wantErr bool
+	}{
+		{name: "i2c-3", want: 3},
+		{name: "i2c-12\n", want: 12},
+		{name: "not-a-bus", wantErr: true},
+	} {
+		n, err := parseBusIndex(test.name)
+		if test.wantErr {
+			if err == nil {
+				t.Errorf("%q: expected an error", test.name)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", test.name, err)
+			continue
+		}
+		if n != test.want {
+			t.Errorf("%q: got %d, want %d", test.name, n, test.want)
+		}
+	}
+}
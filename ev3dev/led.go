@@ -0,0 +1,73 @@
+// Copyright ©2016 The ev3go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ev3dev
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strconv"
+)
+
+// LED is a handle to an ev3dev LED, such as one of the EV3 brick's
+// status LEDs.
+type LED struct {
+	// Name identifies the LED's directory under LEDPath, for
+	// example "led0:green:brick-status".
+	Name fmt.Stringer
+}
+
+func (l *LED) path(attr string) string {
+	return filepath.Join(LEDPath, l.Name.String(), attr)
+}
+
+// MaxBrightness returns the maximum brightness value accepted by
+// SetBrightness for the LED.
+func (l *LED) MaxBrightness() (int, error) {
+	b, err := ioutil.ReadFile(l.path(maxBrightness))
+	if err != nil {
+		return 0, fmt.Errorf("ev3dev: failed to read LED max brightness: %v", err)
+	}
+	max, err := strconv.Atoi(string(chomp(b)))
+	if err != nil {
+		return 0, fmt.Errorf("ev3dev: failed to parse LED max brightness: %v", err)
+	}
+	return max, nil
+}
+
+// Brightness returns the LED's current brightness.
+func (l *LED) Brightness() (int, error) {
+	b, err := ioutil.ReadFile(l.path(brightness))
+	if err != nil {
+		return 0, fmt.Errorf("ev3dev: failed to read LED brightness: %v", err)
+	}
+	v, err := strconv.Atoi(string(chomp(b)))
+	if err != nil {
+		return 0, fmt.Errorf("ev3dev: failed to parse LED brightness: %v", err)
+	}
+	return v, nil
+}
+
+// SetBrightness sets the LED's brightness.
+func (l *LED) SetBrightness(v int) error {
+	if err := ioutil.WriteFile(l.path(brightness), []byte(strconv.Itoa(v)), 0644); err != nil {
+		return fmt.Errorf("ev3dev: failed to set LED brightness: %v", err)
+	}
+	return nil
+}
+
+// On sets the LED to its maximum brightness.
+func (l *LED) On() error {
+	max, err := l.MaxBrightness()
+	if err != nil {
+		return err
+	}
+	return l.SetBrightness(max)
+}
+
+// Off turns the LED off.
+func (l *LED) Off() error {
+	return l.SetBrightness(0)
+}
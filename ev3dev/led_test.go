@@ -0,0 +1,92 @@
+// Copyright ©2016 The ev3go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ev3dev
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+)
+
+type stringer string
+
+func (s stringer) String() string { return string(s) }
+
+// withFakeLED points LEDPath at a temporary directory containing a
+// single LED directory called name, with max_brightness and
+// brightness files holding the given content, and returns a
+// function that restores LEDPath.
+func withFakeLED(t *testing.T, name string, maxBright, bright int) func() {
+	t.Helper()
+
+	dir := t.TempDir()
+	ledDir := filepath.Join(dir, name)
+	if err := os.Mkdir(ledDir, 0755); err != nil {
+		t.Fatalf("failed to create fake LED directory: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(ledDir, maxBrightness), []byte(strconv.Itoa(maxBright)), 0644); err != nil {
+		t.Fatalf("failed to write fake max_brightness: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(ledDir, brightness), []byte(strconv.Itoa(bright)), 0644); err != nil {
+		t.Fatalf("failed to write fake brightness: %v", err)
+	}
+
+	old := LEDPath
+	LEDPath = dir
+	return func() { LEDPath = old }
+}
+
+func TestLEDMaxBrightness(t *testing.T) {
+	defer withFakeLED(t, "led0:green:brick-status", 255, 0)()
+
+	led := &LED{Name: stringer("led0:green:brick-status")}
+	got, err := led.MaxBrightness()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got != 255 {
+		t.Errorf("got max brightness %d, want 255", got)
+	}
+}
+
+func TestLEDBrightnessAndSetBrightness(t *testing.T) {
+	defer withFakeLED(t, "led0:green:brick-status", 255, 0)()
+
+	led := &LED{Name: stringer("led0:green:brick-status")}
+	if got, err := led.Brightness(); err != nil || got != 0 {
+		t.Fatalf("got brightness (%d, %v), want (0, nil)", got, err)
+	}
+
+	if err := led.SetBrightness(128); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	got, err := led.Brightness()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got != 128 {
+		t.Errorf("got brightness %d after SetBrightness, want 128", got)
+	}
+}
+
+func TestLEDOnOff(t *testing.T) {
+	defer withFakeLED(t, "led0:green:brick-status", 255, 0)()
+
+	led := &LED{Name: stringer("led0:green:brick-status")}
+	if err := led.On(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got, err := led.Brightness(); err != nil || got != 255 {
+		t.Fatalf("got brightness (%d, %v) after On, want (255, nil)", got, err)
+	}
+
+	if err := led.Off(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got, err := led.Brightness(); err != nil || got != 0 {
+		t.Fatalf("got brightness (%d, %v) after Off, want (0, nil)", got, err)
+	}
+}
@@ -0,0 +1,186 @@
+// Copyright ©2017 The ev3go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ev3dev
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// motorGroupPollRate is the interval at which a Group polls the
+// state of its member motors while waiting for them to finish
+// moving.
+const motorGroupPollRate = 100 * time.Millisecond
+
+// motor is the subset of *TachoMotor's behaviour that Group depends
+// on. It exists so that Group's logic can be exercised in tests
+// against a fake, rather than a motor backed by real sysfs files.
+type motor interface {
+	Position() (int, error)
+	SetSpeedSP(int) (*TachoMotor, error)
+	SetPositionSP(int) (*TachoMotor, error)
+	Command(string) (*TachoMotor, error)
+	State() (MotorState, error)
+	SetStopAction(string) (*TachoMotor, error)
+}
+
+// Group synchronizes a set of TachoMotors so that a multi-motor
+// move, such as a differential drive or a linked-joint arm, starts
+// and finishes together rather than running each motor to its own
+// target independently.
+//
+// A Group is not safe for concurrent use.
+type Group struct {
+	motors []motor
+
+	targets  []int
+	duration time.Duration
+
+	err error
+}
+
+// NewMotorGroup returns a Group wrapping the given motors, in the
+// order given. That order is used to match up the positions passed
+// to SetTargets.
+func NewMotorGroup(motors ...*TachoMotor) *Group {
+	ms := make([]motor, len(motors))
+	for i, m := range motors {
+		ms[i] = m
+	}
+	return newGroup(ms...)
+}
+
+// newGroup is the motor-interface-typed constructor underlying
+// NewMotorGroup, split out so that tests can supply fake motors.
+func newGroup(motors ...motor) *Group {
+	return &Group{motors: motors}
+}
+
+// SetTargets sets the absolute target position for each motor in
+// the group, one position per motor in the order given to
+// NewMotorGroup.
+func (g *Group) SetTargets(positions ...int) *Group {
+	if g.err != nil {
+		return g
+	}
+	if len(positions) != len(g.motors) {
+		g.err = fmt.Errorf("ev3dev: target count %d does not match motor count %d", len(positions), len(g.motors))
+		return g
+	}
+	g.targets = positions
+	return g
+}
+
+// SetDuration sets how long the synchronized move started by Run
+// should take.
+func (g *Group) SetDuration(d time.Duration) *Group {
+	g.duration = d
+	return g
+}
+
+// Run starts every motor in the group moving to the position given
+// to SetTargets, scaling each motor's speed so that all motors are
+// expected to reach their targets at the same time, d after Run is
+// called, where d is the duration given to SetDuration.
+func (g *Group) Run() error {
+	if g.err != nil {
+		return g.err
+	}
+	if len(g.targets) != len(g.motors) {
+		return fmt.Errorf("ev3dev: motor group has no targets set")
+	}
+	if g.duration <= 0 {
+		return fmt.Errorf("ev3dev: motor group has no duration set")
+	}
+
+	for i, m := range g.motors {
+		pos, err := m.Position()
+		if err != nil {
+			return err
+		}
+		speed := int(float64(g.targets[i]-pos) / g.duration.Seconds())
+		if _, err := m.SetSpeedSP(speed); err != nil {
+			return err
+		}
+		if _, err := m.SetPositionSP(g.targets[i]); err != nil {
+			return err
+		}
+	}
+	for _, m := range g.motors {
+		if _, err := m.Command(runToAbsPos); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Wait blocks until every motor in the group's state no longer
+// intersects mask, for example Running to wait for all motors to
+// stop moving, polling each motor's state in its own goroutine. It
+// reports whether any motor's state included Stalled at any point
+// during the wait, and the first error encountered reading a
+// motor's state, if any.
+func (g *Group) Wait(mask MotorState) (stalled bool, err error) {
+	var (
+		mu       sync.Mutex
+		wg       sync.WaitGroup
+		composed MotorState
+	)
+
+	wg.Add(len(g.motors))
+	for _, m := range g.motors {
+		go func(m motor) {
+			defer wg.Done()
+			for {
+				s, e := m.State()
+				if e != nil {
+					mu.Lock()
+					if err == nil {
+						err = e
+					}
+					mu.Unlock()
+					return
+				}
+
+				mu.Lock()
+				composed |= s
+				mu.Unlock()
+
+				if s&mask == 0 {
+					return
+				}
+				time.Sleep(motorGroupPollRate)
+			}
+		}(m)
+	}
+	wg.Wait()
+
+	return composed&Stalled != 0, err
+}
+
+// Stop sets the stop action of every motor in the group to action
+// and then commands them all to stop.
+func (g *Group) Stop(action string) error {
+	for _, m := range g.motors {
+		if _, err := m.SetStopAction(action); err != nil {
+			return err
+		}
+	}
+	for _, m := range g.motors {
+		if _, err := m.Command(cmdStop); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Tacho-motor commands used by Group. These mirror the values
+// documented for the command attribute at
+// https://www.ev3dev.org/docs/drivers/tacho-motor-class/.
+const (
+	runToAbsPos = "run-to-abs-pos"
+	cmdStop     = "stop"
+)
@@ -0,0 +1,161 @@
+// Copyright ©2017 The ev3go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ev3dev
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// fakeMotor is a fake motor used to exercise Group's logic without
+// a TachoMotor backed by real sysfs files.
+type fakeMotor struct {
+	pos int
+
+	speedSP  int
+	posSP    int
+	commands []string
+	stopAct  string
+
+	// State reports state for the first statePolls calls, then
+	// transitions to doneState, so that a Group waiting on this
+	// motor is guaranteed to eventually see its wait mask clear.
+	state      MotorState
+	statePolls int
+	doneState  MotorState
+	stateCalls int
+
+	stateErr error
+}
+
+func (m *fakeMotor) Position() (int, error) { return m.pos, nil }
+
+func (m *fakeMotor) SetSpeedSP(sp int) (*TachoMotor, error) {
+	m.speedSP = sp
+	return nil, nil
+}
+
+func (m *fakeMotor) SetPositionSP(pos int) (*TachoMotor, error) {
+	m.posSP = pos
+	return nil, nil
+}
+
+func (m *fakeMotor) Command(c string) (*TachoMotor, error) {
+	m.commands = append(m.commands, c)
+	return nil, nil
+}
+
+func (m *fakeMotor) State() (MotorState, error) {
+	if m.stateErr != nil {
+		return 0, m.stateErr
+	}
+	if m.stateCalls < m.statePolls {
+		m.stateCalls++
+		return m.state, nil
+	}
+	return m.doneState, nil
+}
+
+func (m *fakeMotor) SetStopAction(action string) (*TachoMotor, error) {
+	m.stopAct = action
+	return nil, nil
+}
+
+func TestGroupSetTargetsCountMismatch(t *testing.T) {
+	g := newGroup(&fakeMotor{}, &fakeMotor{})
+	g.SetTargets(1, 2, 3)
+	if g.err == nil {
+		t.Fatal("expected an error from SetTargets with a target count that does not match the motor count")
+	}
+	if err := g.Run(); err == nil {
+		t.Fatal("expected Run to return the SetTargets error")
+	}
+}
+
+func TestGroupRunNoTargets(t *testing.T) {
+	g := newGroup(&fakeMotor{}, &fakeMotor{})
+	if err := g.SetDuration(time.Second).Run(); err == nil {
+		t.Fatal("expected Run to fail when no targets have been set")
+	}
+}
+
+func TestGroupRunNoDuration(t *testing.T) {
+	g := newGroup(&fakeMotor{})
+	if err := g.SetTargets(100).Run(); err == nil {
+		t.Fatal("expected Run to fail when no duration has been set")
+	}
+}
+
+func TestGroupRunSpeedScaling(t *testing.T) {
+	near := &fakeMotor{pos: 0}
+	far := &fakeMotor{pos: 0}
+	g := newGroup(near, far)
+	g.SetTargets(100, 400).SetDuration(2 * time.Second)
+
+	if err := g.Run(); err != nil {
+		t.Fatalf("unexpected error from Run: %v", err)
+	}
+
+	// far must travel 4x the distance of near in the same duration,
+	// so its speed setpoint must be scaled up by the same factor.
+	if far.speedSP != 4*near.speedSP {
+		t.Errorf("expected far motor's speed to be scaled to 4x near motor's: got near=%d far=%d", near.speedSP, far.speedSP)
+	}
+	if near.speedSP != 50 || far.speedSP != 200 {
+		t.Errorf("unexpected scaled speeds: got near=%d far=%d, want near=50 far=200", near.speedSP, far.speedSP)
+	}
+	if near.posSP != 100 || far.posSP != 400 {
+		t.Errorf("unexpected position setpoints: got near=%d far=%d", near.posSP, far.posSP)
+	}
+	for _, m := range []*fakeMotor{near, far} {
+		if len(m.commands) != 1 || m.commands[0] != runToAbsPos {
+			t.Errorf("expected motor to receive a single %q command, got %v", runToAbsPos, m.commands)
+		}
+	}
+}
+
+func TestGroupWaitStalled(t *testing.T) {
+	// running reports Running|Stalled for one poll, then stops, so
+	// that Wait is guaranteed to see the stall and still return.
+	running := &fakeMotor{state: Running | Stalled, statePolls: 1, doneState: 0}
+	stopped := &fakeMotor{state: 0}
+	g := newGroup(running, stopped)
+
+	stalled, err := g.Wait(Running)
+	if err != nil {
+		t.Fatalf("unexpected error from Wait: %v", err)
+	}
+	if !stalled {
+		t.Error("expected Wait to report stalled=true when a motor's state includes Stalled")
+	}
+}
+
+func TestGroupWaitError(t *testing.T) {
+	wantErr := errors.New("ev3dev: fake read error")
+	g := newGroup(&fakeMotor{stateErr: wantErr})
+
+	if _, err := g.Wait(Running); err != wantErr {
+		t.Errorf("expected Wait to return the motor's State error, got %v", err)
+	}
+}
+
+func TestGroupStop(t *testing.T) {
+	a := &fakeMotor{}
+	b := &fakeMotor{}
+	g := newGroup(a, b)
+
+	if err := g.Stop("brake"); err != nil {
+		t.Fatalf("unexpected error from Stop: %v", err)
+	}
+	for _, m := range []*fakeMotor{a, b} {
+		if m.stopAct != "brake" {
+			t.Errorf("expected stop action %q, got %q", "brake", m.stopAct)
+		}
+		if len(m.commands) != 1 || m.commands[0] != cmdStop {
+			t.Errorf("expected motor to receive a single %q command, got %v", cmdStop, m.commands)
+		}
+	}
+}
@@ -0,0 +1,181 @@
+// Copyright ©2017 The ev3go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package onewire provides access to 1-Wire devices attached to an
+// ev3dev sensor port through a w1-gpio overlay, such as DS18B20
+// temperature sensors.
+package onewire
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// busPath is the sysfs path at which the Linux w1 subsystem
+// enumerates discovered 1-Wire devices. It is a var rather than a
+// const so that tests can point it at a fake w1 devices tree.
+var busPath = "/sys/bus/w1/devices"
+
+// ds18b20Family is the 1-Wire family code for Maxim DS18B20
+// temperature sensors.
+const ds18b20Family = 0x28
+
+// Bus is a handle to the system's 1-Wire bus.
+type Bus struct{}
+
+// NewBus returns a Bus for the 1-Wire devices enumerated under
+// /sys/bus/w1/devices.
+func NewBus() *Bus { return &Bus{} }
+
+// Device is a 1-Wire device discovered on the bus.
+type Device struct {
+	family byte
+	id     uint64
+	name   string
+}
+
+// Family returns the device's 1-Wire family code, which identifies
+// its type (0x28 for a DS18B20, for example).
+func (d Device) Family() byte { return d.family }
+
+// ID returns the device's 48-bit serial number.
+func (d Device) ID() uint64 { return d.id }
+
+// String returns the device's sysfs directory name, in the form
+// used by the kernel's w1 subsystem: "family-serial" in hex.
+func (d Device) String() string { return d.name }
+
+// Devices returns the 1-Wire devices currently enumerated on the
+// bus.
+func (b *Bus) Devices() ([]Device, error) {
+	entries, err := os.ReadDir(busPath)
+	if err != nil {
+		return nil, fmt.Errorf("onewire: failed to list %s: %v", busPath, err)
+	}
+
+	var devices []Device
+	for _, e := range entries {
+		name := e.Name()
+		if strings.HasPrefix(name, "w1_bus_master") {
+			continue
+		}
+		family, serial, ok := strings.Cut(name, "-")
+		if !ok {
+			continue
+		}
+		f, err := strconv.ParseUint(family, 16, 8)
+		if err != nil {
+			continue
+		}
+		id, err := strconv.ParseUint(serial, 16, 64)
+		if err != nil {
+			continue
+		}
+		devices = append(devices, Device{family: byte(f), id: id, name: name})
+	}
+	return devices, nil
+}
+
+// CRCError is returned when a 1-Wire device's reported CRC check
+// has failed.
+type CRCError struct {
+	// Name is the sysfs directory name of the device that
+	// failed its CRC check.
+	Name string
+}
+
+func (e CRCError) Error() string {
+	return fmt.Sprintf("onewire: crc check failed for device %s", e.Name)
+}
+
+// DS18B20 is a Device known to be a Maxim DS18B20 temperature
+// sensor.
+type DS18B20 struct {
+	Device
+}
+
+// Temperature reads and returns the sensor's current temperature in
+// degrees Celsius. It returns a CRCError if the kernel driver's CRC
+// check on the 1-Wire transaction failed.
+func (d DS18B20) Temperature() (float64, error) {
+	path := busPath + "/" + d.name + "/w1_slave"
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return 0, fmt.Errorf("onewire: failed to read %s: %v", path, err)
+	}
+
+	lines := strings.Split(strings.TrimRight(string(b), "\n"), "\n")
+	if len(lines) != 2 || !strings.HasSuffix(strings.TrimRight(lines[0], "\n"), "YES") {
+		return 0, CRCError{Name: d.name}
+	}
+
+	_, raw, ok := strings.Cut(lines[1], "t=")
+	if !ok {
+		return 0, fmt.Errorf("onewire: could not find temperature in %s", path)
+	}
+	milliC, err := strconv.Atoi(raw)
+	if err != nil {
+		return 0, fmt.Errorf("onewire: could not parse temperature %q: %v", raw, err)
+	}
+	return float64(milliC) / 1000, nil
+}
+
+// Reading is a single temperature sample taken from a DS18B20
+// during a Bus.Poll.
+type Reading struct {
+	Device DS18B20
+	Temp   float64
+	Err    error
+}
+
+// Poll starts a goroutine that, every interval, reads the
+// temperature of every DS18B20 enumerated on the bus and sends a
+// Reading for each on the returned channel. The goroutine runs
+// until the returned stop function is called; stop blocks until the
+// goroutine has exited and the channel has been closed, so no more
+// readings can arrive once it returns.
+func (b *Bus) Poll(interval time.Duration) (readings <-chan Reading, stop func()) {
+	out := make(chan Reading)
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+
+	go func() {
+		defer close(stopped)
+		defer close(out)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				devs, err := b.Devices()
+				if err != nil {
+					continue
+				}
+				for _, dev := range devs {
+					if dev.Family() != ds18b20Family {
+						continue
+					}
+					ds := DS18B20{dev}
+					t, err := ds.Temperature()
+					select {
+					case out <- Reading{Device: ds, Temp: t, Err: err}:
+					case <-done:
+						return
+					}
+				}
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	return out, func() {
+		close(done)
+		<-stopped
+	}
+}
@@ -0,0 +1,140 @@
+// Copyright ©2017 The ev3go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package onewire
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// withFakeBus points busPath at a temporary directory containing
+// one subdirectory per name in devices, each holding a w1_slave file
+// with the given content, and returns a function that restores
+// busPath. A w1_bus_master entry is also created, to be skipped by
+// Devices the same way it would be against a real bus.
+func withFakeBus(t *testing.T, devices map[string]string) func() {
+	t.Helper()
+
+	dir := t.TempDir()
+	if err := os.Mkdir(filepath.Join(dir, "w1_bus_master1"), 0755); err != nil {
+		t.Fatalf("failed to create fake bus master entry: %v", err)
+	}
+	for name, w1Slave := range devices {
+		if err := os.Mkdir(filepath.Join(dir, name), 0755); err != nil {
+			t.Fatalf("failed to create fake device directory: %v", err)
+		}
+		path := filepath.Join(dir, name, "w1_slave")
+		if err := os.WriteFile(path, []byte(w1Slave), 0644); err != nil {
+			t.Fatalf("failed to write fake w1_slave: %v", err)
+		}
+	}
+
+	old := busPath
+	busPath = dir
+	return func() { busPath = old }
+}
+
+func TestBusDevices(t *testing.T) {
+	defer withFakeBus(t, map[string]string{
+		"28-0000001234ab": "",
+		"10-0000abcdef01": "",
+		"not-a-device":    "",
+	})()
+
+	devices, err := NewBus().Devices()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(devices) != 2 {
+		t.Fatalf("got %d devices, want 2: %v", len(devices), devices)
+	}
+
+	byName := make(map[string]Device)
+	for _, d := range devices {
+		byName[d.String()] = d
+	}
+	want := map[string]byte{
+		"28-0000001234ab": 0x28,
+		"10-0000abcdef01": 0x10,
+	}
+	for name, family := range want {
+		d, ok := byName[name]
+		if !ok {
+			t.Errorf("missing device %s", name)
+			continue
+		}
+		if d.Family() != family {
+			t.Errorf("device %s: got family %#x, want %#x", name, d.Family(), family)
+		}
+	}
+	if id := byName["28-0000001234ab"].ID(); id != 0x0000001234ab {
+		t.Errorf("device 28-0000001234ab: got id %#x, want %#x", id, 0x0000001234ab)
+	}
+}
+
+func TestDS18B20TemperatureOK(t *testing.T) {
+	const name = "28-0000001234ab"
+	defer withFakeBus(t, map[string]string{
+		name: "1a 01 4b 46 7f ff 0c 10 74 : crc=74 YES\n1a 01 4b 46 7f ff 0c 10 74 t=26625\n",
+	})()
+
+	devices, err := NewBus().Devices()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	ds := DS18B20{devices[0]}
+	temp, err := ds.Temperature()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if temp != 26.625 {
+		t.Errorf("got temperature %v, want 26.625", temp)
+	}
+}
+
+func TestDS18B20TemperatureCRCFail(t *testing.T) {
+	const name = "28-0000001234ab"
+	defer withFakeBus(t, map[string]string{
+		name: "1a 01 4b 46 7f ff 0c 10 74 : crc=74 NO\n1a 01 4b 46 7f ff 0c 10 74 t=26625\n",
+	})()
+
+	devices, err := NewBus().Devices()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	ds := DS18B20{devices[0]}
+	if _, err := ds.Temperature(); err != (CRCError{Name: name}) {
+		t.Errorf("got error %v, want %v", err, CRCError{Name: name})
+	}
+}
+
+func TestBusPoll(t *testing.T) {
+	defer withFakeBus(t, map[string]string{
+		"28-0000001234ab": "1a 01 4b 46 7f ff 0c 10 74 : crc=74 YES\n1a 01 4b 46 7f ff 0c 10 74 t=26625\n",
+	})()
+
+	readings, stop := NewBus().Poll(time.Millisecond)
+
+	r, ok := <-readings
+	if !ok {
+		t.Fatal("readings channel closed before a reading was sent")
+	}
+	if r.Err != nil {
+		t.Fatalf("unexpected error in reading: %v", r.Err)
+	}
+	if r.Temp != 26.625 {
+		t.Errorf("got temperature %v, want 26.625", r.Temp)
+	}
+
+	// stop blocks until the poll goroutine has exited and closed
+	// readings, so no further reading can arrive after it returns.
+	stop()
+
+	if _, ok := <-readings; ok {
+		t.Fatal("expected readings to be closed after stop")
+	}
+}
@@ -0,0 +1,186 @@
+// Copyright ©2017 The ev3go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ev3dev
+
+import (
+	"fmt"
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+// ALSA PCM parameter indices, from <sound/asound.h>.
+const (
+	paramAccess    = 0
+	paramFormat    = 1
+	paramSubformat = 2
+
+	paramSampleBits = 8
+	paramFrameBits  = 9
+	paramChannels   = 10
+	paramRate       = 11
+	paramPeriodSize = 13
+	paramPeriods    = 15
+	paramBufferSize = 17
+
+	firstMask     = paramAccess
+	lastMask      = paramSubformat
+	firstInterval = paramSampleBits
+	lastInterval  = paramBufferSize + 2 // SNDRV_PCM_HW_PARAM_TICK_TIME
+
+	accessRWInterleaved = 3
+	formatS8            = 0
+	formatU8            = 1
+	formatS16LE         = 2
+)
+
+// sndMask mirrors struct snd_mask.
+type sndMask struct {
+	bits [256 / 32]uint32
+}
+
+func (m *sndMask) set(bit uint32) {
+	for i := range m.bits {
+		m.bits[i] = 0
+	}
+	m.bits[bit>>5] = 1 << (bit & 31)
+}
+
+// sndInterval mirrors struct snd_interval. flags packs the
+// openmin, openmax, integer and empty bitfields into its low four
+// bits; only integer (bit 2) is used here, to pin the interval to
+// an exact value.
+type sndInterval struct {
+	min, max uint32
+	flags    uint32
+}
+
+func exactInterval(v uint32) sndInterval {
+	return sndInterval{min: v, max: v, flags: 1 << 2}
+}
+
+// sndPCMHwParams mirrors struct snd_pcm_hw_params.
+type sndPCMHwParams struct {
+	flags     uint32
+	masks     [lastMask - firstMask + 1]sndMask
+	mres      [5]sndMask
+	intervals [lastInterval - firstInterval + 1]sndInterval
+	ires      [9]sndInterval
+	rmask     uint32
+	cmask     uint32
+	info      uint32
+	msbits    uint32
+	rateNum   uint32
+	rateDen   uint32
+	fifoSize  uint64
+	reserved  [64]byte
+}
+
+// newHwParams builds the hw_params value used to configure the PCM
+// device for format, pinning the access mode, sample format, sample
+// and frame widths, channel count and rate to exact values. The
+// subformat, period and buffer parameters are left at their full
+// ranges for the driver to choose.
+func newHwParams(format PCMFormat) (*sndPCMHwParams, error) {
+	alsaFormat, err := format.alsaFormat()
+	if err != nil {
+		return nil, err
+	}
+	if format.Rate <= 0 {
+		return nil, fmt.Errorf("ev3dev: invalid PCM sample rate: %d", format.Rate)
+	}
+	if format.Channels <= 0 {
+		return nil, fmt.Errorf("ev3dev: invalid PCM channel count: %d", format.Channels)
+	}
+
+	var p sndPCMHwParams
+	for i := range p.masks {
+		for j := range p.masks[i].bits {
+			p.masks[i].bits[j] = 0xffffffff
+		}
+	}
+	for i := range p.intervals {
+		p.intervals[i] = sndInterval{min: 0, max: 0xffffffff}
+	}
+
+	p.masks[paramAccess-firstMask].set(accessRWInterleaved)
+	p.masks[paramFormat-firstMask].set(alsaFormat)
+	p.intervals[paramSampleBits-firstInterval] = exactInterval(uint32(format.Width))
+	p.intervals[paramFrameBits-firstInterval] = exactInterval(uint32(format.Width * format.Channels))
+	p.intervals[paramChannels-firstInterval] = exactInterval(uint32(format.Channels))
+	p.intervals[paramRate-firstInterval] = exactInterval(uint32(format.Rate))
+
+	return &p, nil
+}
+
+// sndXferi mirrors struct snd_xferi, used by the writei ioctl.
+type sndXferi struct {
+	result uint64
+	buf    unsafe.Pointer
+	frames uint64
+}
+
+// ALSA PCM ioctl requests, from <sound/asound.h>. The request
+// numbers are computed with the Linux _IOC encoding: direction in
+// the top bits, then size, type ('A') and number.
+var (
+	sndrvPCMIoctlHwParams     = iocWR('A', 0x11, unsafe.Sizeof(sndPCMHwParams{}))
+	sndrvPCMIoctlPrepare      = ioc('A', 0x40, 0)
+	sndrvPCMIoctlWriteiFrames = iocW('A', 0x50, unsafe.Sizeof(sndXferi{}))
+)
+
+const (
+	iocNRBITS   = 8
+	iocTYPEBITS = 8
+	iocSIZEBITS = 14
+
+	iocNRSHIFT   = 0
+	iocTYPESHIFT = iocNRSHIFT + iocNRBITS
+	iocSIZESHIFT = iocTYPESHIFT + iocTYPEBITS
+	iocDIRSHIFT  = iocSIZESHIFT + iocSIZEBITS
+
+	iocNone  = 0
+	iocWrite = 1
+	iocRead  = 2
+)
+
+func iocEncode(dir, typ, nr uint, size uintptr) uintptr {
+	return uintptr(dir)<<iocDIRSHIFT | uintptr(typ)<<iocTYPESHIFT | uintptr(nr)<<iocNRSHIFT | size<<iocSIZESHIFT
+}
+
+func ioc(typ byte, nr uint, size uintptr) uintptr {
+	return iocEncode(iocNone, uint(typ), nr, size)
+}
+
+func iocW(typ byte, nr uint, size uintptr) uintptr {
+	return iocEncode(iocWrite, uint(typ), nr, size)
+}
+
+func iocWR(typ byte, nr uint, size uintptr) uintptr {
+	return iocEncode(iocWrite|iocRead, uint(typ), nr, size)
+}
+
+// pcmIoctl issues the given ioctl request against fd, passing arg as
+// the request's argument pointer.
+func pcmIoctl(fd uintptr, req uintptr, arg unsafe.Pointer) error {
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, fd, req, uintptr(arg))
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
+
+// pcmWritei writes frames frames worth of interleaved samples from
+// buf to the PCM device f.
+func pcmWritei(f *os.File, buf []byte, frames int) error {
+	if len(buf) == 0 {
+		return nil
+	}
+	x := sndXferi{buf: unsafe.Pointer(&buf[0]), frames: uint64(frames)}
+	if err := pcmIoctl(f.Fd(), sndrvPCMIoctlWriteiFrames, unsafe.Pointer(&x)); err != nil {
+		return fmt.Errorf("ev3dev: failed to write PCM frames: %v", err)
+	}
+	return nil
+}
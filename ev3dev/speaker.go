@@ -0,0 +1,260 @@
+// Copyright ©2017 The ev3go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ev3dev
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+	"unsafe"
+)
+
+// Speaker is a handle to the ev3 speaker. Tone and Play sound
+// tones through the event-input beeper; PlayPCM and PlayWAV play
+// arbitrary audio through the sound card's ALSA PCM device. It
+// must be initialized before use.
+type Speaker struct {
+	eventPath string
+	pcmPath   string
+
+	// Blocking controls what PlayPCM does when called while
+	// another PlayPCM call on this Speaker is already in
+	// progress: if true, the call waits for the bus to free up;
+	// if false, it returns ErrBusy immediately.
+	Blocking bool
+
+	busy chan struct{}
+}
+
+// NewSpeaker returns a Speaker that plays tones through the
+// event-input device at path, and PCM audio through the ev3's
+// sound card.
+func NewSpeaker(path string) *Speaker {
+	s := &Speaker{
+		eventPath: path,
+		pcmPath:   "/dev/snd/pcmC0D0p",
+		busy:      make(chan struct{}, 1),
+	}
+	s.busy <- struct{}{}
+	return s
+}
+
+// ErrBusy is returned by PlayPCM when the Speaker is already
+// playing and Blocking is false.
+var ErrBusy = errors.New("ev3dev: speaker is busy")
+
+// input_event type and code for the legoev3 sound driver's beeper.
+const (
+	evSnd   = 0x12
+	sndTone = 0x02
+)
+
+// inputEvent mirrors struct input_event from linux/input.h on a
+// 64-bit system.
+type inputEvent struct {
+	sec, usec uint64
+	typ, code uint16
+	value     int32
+}
+
+func (s *Speaker) tone(freqHz int) error {
+	f, err := os.OpenFile(s.eventPath, os.O_WRONLY, 0)
+	if err != nil {
+		return fmt.Errorf("ev3dev: failed to open speaker event device: %v", err)
+	}
+	defer f.Close()
+
+	ev := inputEvent{typ: evSnd, code: sndTone, value: int32(freqHz)}
+	return binary.Write(f, binary.LittleEndian, ev)
+}
+
+// Tone sounds a continuous tone at freqHz until stopped by a
+// subsequent call to Tone with freqHz of zero, or by Play.
+func (s *Speaker) Tone(freqHz int) error {
+	return s.tone(freqHz)
+}
+
+// Play sounds a tone at freqHz for duration d, blocking until it
+// has finished.
+func (s *Speaker) Play(freqHz int, d time.Duration) error {
+	if err := s.tone(freqHz); err != nil {
+		return err
+	}
+	time.Sleep(d)
+	return s.tone(0)
+}
+
+// PCMFormat describes the layout of the PCM samples passed to
+// PlayPCM.
+type PCMFormat struct {
+	// Rate is the number of samples per second, per channel.
+	Rate int
+
+	// Channels is the number of interleaved channels.
+	Channels int
+
+	// Width is the number of bits per sample: 8 for U8 samples,
+	// or 16 for S16_LE samples.
+	Width int
+}
+
+func (f PCMFormat) frameSize() int { return f.Channels * f.Width / 8 }
+
+func (f PCMFormat) alsaFormat() (uint32, error) {
+	switch f.Width {
+	case 8:
+		return formatU8, nil
+	case 16:
+		return formatS16LE, nil
+	default:
+		return 0, fmt.Errorf("ev3dev: unsupported PCM sample width: %d", f.Width)
+	}
+}
+
+// lockBus acquires exclusive use of the PCM device, respecting
+// Blocking.
+func (s *Speaker) lockBus() error {
+	if s.Blocking {
+		<-s.busy
+		return nil
+	}
+	select {
+	case <-s.busy:
+		return nil
+	default:
+		return ErrBusy
+	}
+}
+
+func (s *Speaker) unlockBus() { s.busy <- struct{}{} }
+
+// PlayPCM plays the PCM samples read from r, described by format,
+// through the ev3's ALSA PCM device. If another PlayPCM call is
+// already in progress, PlayPCM either blocks or returns ErrBusy,
+// according to Blocking.
+func (s *Speaker) PlayPCM(r io.Reader, format PCMFormat) error {
+	if err := s.lockBus(); err != nil {
+		return err
+	}
+	defer s.unlockBus()
+
+	f, err := os.OpenFile(s.pcmPath, os.O_WRONLY, 0)
+	if err != nil {
+		return fmt.Errorf("ev3dev: failed to open PCM device %s: %v", s.pcmPath, err)
+	}
+	defer f.Close()
+
+	params, err := newHwParams(format)
+	if err != nil {
+		return err
+	}
+	if err := pcmIoctl(f.Fd(), sndrvPCMIoctlHwParams, unsafe.Pointer(params)); err != nil {
+		return fmt.Errorf("ev3dev: failed to set PCM hw params: %v", err)
+	}
+	if err := pcmIoctl(f.Fd(), sndrvPCMIoctlPrepare, nil); err != nil {
+		return fmt.Errorf("ev3dev: failed to prepare PCM device: %v", err)
+	}
+
+	frame := format.frameSize()
+	buf := make([]byte, (4096/frame)*frame)
+	br := bufio.NewReader(r)
+	for {
+		n, err := io.ReadFull(br, buf)
+		if n > 0 {
+			if werr := pcmWritei(f, buf[:n-n%frame], n/frame); werr != nil {
+				return werr
+			}
+		}
+		switch err {
+		case nil:
+		case io.EOF, io.ErrUnexpectedEOF:
+			return nil
+		default:
+			return fmt.Errorf("ev3dev: failed to read PCM samples: %v", err)
+		}
+	}
+}
+
+// PlayWAV parses a RIFF/WAVE stream read from r and plays its data
+// chunk through PlayPCM.
+func (s *Speaker) PlayWAV(r io.Reader) error {
+	format, data, err := parseWAV(r)
+	if err != nil {
+		return err
+	}
+	return s.PlayPCM(data, format)
+}
+
+// parseWAV reads the RIFF/WAVE header and chunks from r up to and
+// including the data chunk's header, and returns the format
+// described by the fmt chunk along with a reader limited to the
+// data chunk's bytes. It is split out from PlayWAV so the parsing
+// can be exercised without a PCM device.
+func parseWAV(r io.Reader) (PCMFormat, io.Reader, error) {
+	var riff [12]byte
+	if _, err := io.ReadFull(r, riff[:]); err != nil {
+		return PCMFormat{}, nil, fmt.Errorf("ev3dev: failed to read WAV header: %v", err)
+	}
+	if string(riff[0:4]) != "RIFF" || string(riff[8:12]) != "WAVE" {
+		return PCMFormat{}, nil, errors.New("ev3dev: not a RIFF/WAVE stream")
+	}
+
+	var format PCMFormat
+	for {
+		var hdr [8]byte
+		if _, err := io.ReadFull(r, hdr[:]); err != nil {
+			return PCMFormat{}, nil, fmt.Errorf("ev3dev: failed to read WAV chunk header: %v", err)
+		}
+		id := string(hdr[0:4])
+		size := int64(binary.LittleEndian.Uint32(hdr[4:8]))
+
+		switch id {
+		case "fmt ":
+			var chunk [16]byte
+			if _, err := io.ReadFull(r, chunk[:]); err != nil {
+				return PCMFormat{}, nil, fmt.Errorf("ev3dev: failed to read WAV fmt chunk: %v", err)
+			}
+			format.Channels = int(binary.LittleEndian.Uint16(chunk[2:4]))
+			format.Rate = int(binary.LittleEndian.Uint32(chunk[4:8]))
+			format.Width = int(binary.LittleEndian.Uint16(chunk[14:16]))
+			if size > 16 {
+				if _, err := io.CopyN(io.Discard, r, size-16); err != nil {
+					return PCMFormat{}, nil, err
+				}
+			}
+			if err := skipChunkPad(r, size); err != nil {
+				return PCMFormat{}, nil, err
+			}
+		case "data":
+			if format.Rate == 0 {
+				return PCMFormat{}, nil, errors.New("ev3dev: WAV data chunk found before fmt chunk")
+			}
+			return format, io.LimitReader(r, size), nil
+		default:
+			if _, err := io.CopyN(io.Discard, r, size); err != nil {
+				return PCMFormat{}, nil, fmt.Errorf("ev3dev: failed to skip WAV chunk %q: %v", id, err)
+			}
+			if err := skipChunkPad(r, size); err != nil {
+				return PCMFormat{}, nil, err
+			}
+		}
+	}
+}
+
+// skipChunkPad discards the single padding byte that follows an
+// odd-sized RIFF chunk, so that the next chunk header stays aligned.
+func skipChunkPad(r io.Reader, size int64) error {
+	if size%2 == 0 {
+		return nil
+	}
+	if _, err := io.CopyN(io.Discard, r, 1); err != nil {
+		return fmt.Errorf("ev3dev: failed to skip WAV chunk padding: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,183 @@
+// Copyright ©2017 The ev3go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ev3dev
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPCMFormatAlsaFormat(t *testing.T) {
+	for _, test := range []struct {
+		width   int
+		want    uint32
+		wantErr bool
+	}{
+		{width: 8, want: formatU8},
+		{width: 16, want: formatS16LE},
+		{width: 24, wantErr: true},
+	} {
+		got, err := PCMFormat{Width: test.width}.alsaFormat()
+		if test.wantErr {
+			if err == nil {
+				t.Errorf("width %d: expected an error for an unsupported sample width", test.width)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("width %d: unexpected error: %v", test.width, err)
+			continue
+		}
+		if got != test.want {
+			t.Errorf("width %d: got ALSA format %d, want %d", test.width, got, test.want)
+		}
+	}
+}
+
+func TestNewHwParams(t *testing.T) {
+	p, err := newHwParams(PCMFormat{Rate: 8000, Channels: 1, Width: 8})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := p.intervals[paramRate-firstInterval]; got.min != 8000 || got.max != 8000 {
+		t.Errorf("rate interval: got %+v, want min=max=8000", got)
+	}
+	if got := p.intervals[paramChannels-firstInterval]; got.min != 1 || got.max != 1 {
+		t.Errorf("channels interval: got %+v, want min=max=1", got)
+	}
+	if got := p.intervals[paramSampleBits-firstInterval]; got.min != 8 || got.max != 8 {
+		t.Errorf("sample bits interval: got %+v, want min=max=8", got)
+	}
+
+	for _, test := range []struct {
+		name   string
+		format PCMFormat
+	}{
+		{name: "zero rate", format: PCMFormat{Rate: 0, Channels: 1, Width: 8}},
+		{name: "negative rate", format: PCMFormat{Rate: -1, Channels: 1, Width: 8}},
+		{name: "zero channels", format: PCMFormat{Rate: 8000, Channels: 0, Width: 8}},
+		{name: "unsupported width", format: PCMFormat{Rate: 8000, Channels: 1, Width: 24}},
+	} {
+		if _, err := newHwParams(test.format); err == nil {
+			t.Errorf("%s: expected an error", test.name)
+		}
+	}
+}
+
+func TestSpeakerLockBusNonBlocking(t *testing.T) {
+	s := NewSpeaker("")
+	if err := s.lockBus(); err != nil {
+		t.Fatalf("unexpected error locking an idle bus: %v", err)
+	}
+	if err := s.lockBus(); err != ErrBusy {
+		t.Errorf("got error %v locking a busy bus, want ErrBusy", err)
+	}
+	s.unlockBus()
+	if err := s.lockBus(); err != nil {
+		t.Errorf("unexpected error locking the bus after unlock: %v", err)
+	}
+}
+
+func TestSpeakerLockBusBlocking(t *testing.T) {
+	s := NewSpeaker("")
+	s.Blocking = true
+	if err := s.lockBus(); err != nil {
+		t.Fatalf("unexpected error locking an idle bus: %v", err)
+	}
+
+	acquired := make(chan struct{})
+	go func() {
+		if err := s.lockBus(); err != nil {
+			t.Errorf("unexpected error locking the bus: %v", err)
+		}
+		close(acquired)
+	}()
+
+	select {
+	case <-acquired:
+		t.Fatal("blocking lockBus returned before the bus was unlocked")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	s.unlockBus()
+
+	select {
+	case <-acquired:
+	case <-time.After(time.Second):
+		t.Fatal("blocking lockBus was not released after unlockBus")
+	}
+}
+
+// wavChunk appends a RIFF chunk with the given id and data to buf,
+// padding it to an even length as required by the RIFF format.
+func wavChunk(buf *bytes.Buffer, id string, data []byte) {
+	buf.WriteString(id)
+	binary.Write(buf, binary.LittleEndian, uint32(len(data)))
+	buf.Write(data)
+	if len(data)%2 != 0 {
+		buf.WriteByte(0)
+	}
+}
+
+// fmtChunk builds a 16-byte PCM fmt chunk payload.
+func fmtChunk(channels, rate, width int) []byte {
+	b := make([]byte, 16)
+	binary.LittleEndian.PutUint16(b[0:2], 1) // audio format: PCM
+	binary.LittleEndian.PutUint16(b[2:4], uint16(channels))
+	binary.LittleEndian.PutUint32(b[4:8], uint32(rate))
+	binary.LittleEndian.PutUint32(b[8:12], uint32(rate*channels*width/8))
+	binary.LittleEndian.PutUint16(b[12:14], uint16(channels*width/8))
+	binary.LittleEndian.PutUint16(b[14:16], uint16(width))
+	return b
+}
+
+func TestParseWAVWellFormed(t *testing.T) {
+	var buf bytes.Buffer
+	buf.WriteString("RIFF")
+	binary.Write(&buf, binary.LittleEndian, uint32(0)) // size is unchecked by parseWAV
+	buf.WriteString("WAVE")
+	wavChunk(&buf, "fmt ", fmtChunk(1, 8000, 8))
+	wavChunk(&buf, "LIST", []byte("odd")) // an odd-sized chunk that must be skipped, pad included
+	wavChunk(&buf, "data", []byte{1, 2, 3, 4})
+
+	format, data, err := parseWAV(&buf)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if format != (PCMFormat{Rate: 8000, Channels: 1, Width: 8}) {
+		t.Errorf("got format %+v, want {Rate:8000 Channels:1 Width:8}", format)
+	}
+	got, err := io.ReadAll(data)
+	if err != nil {
+		t.Fatalf("unexpected error reading data chunk: %v", err)
+	}
+	if !bytes.Equal(got, []byte{1, 2, 3, 4}) {
+		t.Errorf("got data %v, want [1 2 3 4]", got)
+	}
+}
+
+func TestParseWAVDataBeforeFmt(t *testing.T) {
+	var buf bytes.Buffer
+	buf.WriteString("RIFF")
+	binary.Write(&buf, binary.LittleEndian, uint32(0))
+	buf.WriteString("WAVE")
+	wavChunk(&buf, "data", []byte{1, 2, 3, 4})
+
+	_, _, err := parseWAV(&buf)
+	if err == nil || !strings.Contains(err.Error(), "before fmt chunk") {
+		t.Errorf("got error %v, want one reporting data before fmt", err)
+	}
+}
+
+func TestParseWAVNotRIFF(t *testing.T) {
+	_, _, err := parseWAV(strings.NewReader("not a riff file at all!!"))
+	if err == nil || !strings.Contains(err.Error(), "not a RIFF/WAVE stream") {
+		t.Errorf("got error %v, want one reporting a non-RIFF stream", err)
+	}
+}
@@ -0,0 +1,143 @@
+// Copyright ©2017 The ev3go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ev3dev
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// TachoMotor is a handle to an ev3dev tacho-motor, a motor fitted
+// with a rotary encoder such as the EV3's large and medium servo
+// motors.
+type TachoMotor struct {
+	id int
+}
+
+// NewTachoMotor returns a TachoMotor for the tacho-motor attached to
+// the given port, such as "outA". If port is empty, the first
+// tacho-motor found is used.
+func NewTachoMotor(port string) (*TachoMotor, error) {
+	if port == "" {
+		names, err := filepath.Glob(filepath.Join(TachoMotorPath, motorPrefix+"*"))
+		if err != nil || len(names) == 0 {
+			return nil, fmt.Errorf("ev3dev: could not find a tacho-motor in %s", TachoMotorPath)
+		}
+		id, err := strconv.Atoi(strings.TrimPrefix(filepath.Base(names[0]), motorPrefix))
+		if err != nil {
+			return nil, fmt.Errorf("ev3dev: could not parse id from device name %q: %v", names[0], err)
+		}
+		return &TachoMotor{id: id}, nil
+	}
+	id, err := deviceIDFor(port, "", new(TachoMotor))
+	if err != nil {
+		if _, ok := err.(DriverMismatch); !ok {
+			return nil, err
+		}
+	}
+	return &TachoMotor{id: id}, nil
+}
+
+// Path returns the tacho-motor sysfs class path.
+func (m *TachoMotor) Path() string { return TachoMotorPath }
+
+// Type returns "motor".
+func (m *TachoMotor) Type() string { return motorPrefix }
+
+// String satisfies the fmt.Stringer interface, returning the
+// motor's sysfs directory name, for example "motor0".
+func (m *TachoMotor) String() string { return fmt.Sprintf("%s%d", motorPrefix, m.id) }
+
+func (m *TachoMotor) attrPath(attr string) string {
+	return filepath.Join(m.Path(), m.String(), attr)
+}
+
+func (m *TachoMotor) readString(attr string) (string, error) {
+	b, err := ioutil.ReadFile(m.attrPath(attr))
+	if err != nil {
+		return "", fmt.Errorf("ev3dev: failed to read %s %s: %v", m, attr, err)
+	}
+	return string(chomp(b)), nil
+}
+
+func (m *TachoMotor) writeString(attr, val string) error {
+	if err := ioutil.WriteFile(m.attrPath(attr), []byte(val), 0644); err != nil {
+		return fmt.Errorf("ev3dev: failed to write %s %s: %v", m, attr, err)
+	}
+	return nil
+}
+
+func (m *TachoMotor) readInt(attr string) (int, error) {
+	s, err := m.readString(attr)
+	if err != nil {
+		return 0, err
+	}
+	v, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Errorf("ev3dev: failed to parse %s %s: %v", m, attr, err)
+	}
+	return v, nil
+}
+
+func (m *TachoMotor) writeInt(attr string, v int) error {
+	return m.writeString(attr, strconv.Itoa(v))
+}
+
+// Position returns the motor's current position in encoder counts.
+func (m *TachoMotor) Position() (int, error) {
+	return m.readInt(position)
+}
+
+// SetSpeedSP sets the motor's speed setpoint, in encoder counts per
+// second, used by the run-to-abs-pos and similar commands.
+func (m *TachoMotor) SetSpeedSP(sp int) (*TachoMotor, error) {
+	if err := m.writeInt(speedSetpoint, sp); err != nil {
+		return m, err
+	}
+	return m, nil
+}
+
+// SetPositionSP sets the motor's position setpoint, in encoder
+// counts, used by the run-to-abs-pos command.
+func (m *TachoMotor) SetPositionSP(pos int) (*TachoMotor, error) {
+	if err := m.writeInt(positionSetpoint, pos); err != nil {
+		return m, err
+	}
+	return m, nil
+}
+
+// Command sends comm to the motor's command attribute, such as
+// "run-to-abs-pos" or "stop".
+func (m *TachoMotor) Command(comm string) (*TachoMotor, error) {
+	if err := m.writeString(command, comm); err != nil {
+		return m, err
+	}
+	return m, nil
+}
+
+// SetStopAction sets the action the motor takes, such as "brake" or
+// "coast", when it receives a stop command.
+func (m *TachoMotor) SetStopAction(action string) (*TachoMotor, error) {
+	if err := m.writeString(stopAction, action); err != nil {
+		return m, err
+	}
+	return m, nil
+}
+
+// State returns the motor's current state flags.
+func (m *TachoMotor) State() (MotorState, error) {
+	s, err := m.readString(state)
+	if err != nil {
+		return 0, err
+	}
+	var ms MotorState
+	for _, tok := range strings.Fields(s) {
+		ms |= motorStateTable[tok]
+	}
+	return ms, nil
+}
@@ -0,0 +1,17 @@
+// Copyright ©2016 The ev3go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package all registers every known ev3dev host with the ev3dev
+// package. Importing it is equivalent to blank-importing each of
+// host/ev3, host/brickpi, host/brickpi3, host/pistorms and
+// host/evb.
+package all
+
+import (
+	_ "github.com/ev3go/ev3/host/brickpi"
+	_ "github.com/ev3go/ev3/host/brickpi3"
+	_ "github.com/ev3go/ev3/host/ev3"
+	_ "github.com/ev3go/ev3/host/evb"
+	_ "github.com/ev3go/ev3/host/pistorms"
+)
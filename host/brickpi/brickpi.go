@@ -0,0 +1,32 @@
+// Copyright ©2016 The ev3go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package brickpi registers the LED handle for the Dexter
+// Industries BrickPi with ev3dev.
+//
+// The BrickPi has no LCD and no beeper, so LCD and Speaker are
+// left unregistered.
+package brickpi
+
+import "github.com/ev3go/ev3/ev3dev"
+
+// LED handles for the BrickPi.
+var (
+	Blue = &ev3dev.LED{Name: led("blue")}
+)
+
+// led is a fmt.Stringer LED name.
+type led string
+
+func (l led) String() string {
+	return "brickpi:led1:" + string(l)
+}
+
+func init() {
+	ev3dev.RegisterHost("brickpi", ev3dev.Host{
+		LED: map[string]*ev3dev.LED{
+			"blue": Blue,
+		},
+	})
+}
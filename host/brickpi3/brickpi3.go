@@ -0,0 +1,34 @@
+// Copyright ©2016 The ev3go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package brickpi3 registers the LED handles for the Dexter
+// Industries BrickPi3 with ev3dev.
+//
+// The BrickPi3 has no LCD and no beeper, so LCD and Speaker are
+// left unregistered.
+package brickpi3
+
+import "github.com/ev3go/ev3/ev3dev"
+
+// LED handles for the BrickPi3.
+var (
+	Left  = &ev3dev.LED{Name: led("led0")}
+	Right = &ev3dev.LED{Name: led("led1")}
+)
+
+// led is a fmt.Stringer LED name.
+type led string
+
+func (l led) String() string {
+	return "brickpi3:" + string(l) + ":blue"
+}
+
+func init() {
+	ev3dev.RegisterHost("brickpi3", ev3dev.Host{
+		LED: map[string]*ev3dev.LED{
+			"left":  Left,
+			"right": Right,
+		},
+	})
+}
@@ -0,0 +1,73 @@
+// Copyright ©2016 The ev3go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package ev3 registers the LED, LCD and Speaker handles for the
+// LEGO MINDSTORMS EV3 brick with ev3dev.
+package ev3
+
+import (
+	"fmt"
+
+	"github.com/ev3go/ev3/ev3dev"
+	"github.com/ev3go/ev3/ev3dev/fb"
+)
+
+const (
+	// LCDWidth is the width of the LCD screen in pixels.
+	LCDWidth = 178
+
+	// LCDHeight is the height of the LCD screen in pixels.
+	LCDHeight = 128
+
+	// LCDStride is the width of the LCD screen memory in bytes.
+	LCDStride = 712
+)
+
+// LCD is the draw image used draw directly to the ev3 LCD screen.
+// Drawing operations are safe for concurrent use, but are not atomic
+// beyond the pixel level.
+var LCD = ev3dev.NewFrameBuffer("/dev/fb0", fb.NewXRGBWith, LCDWidth, LCDHeight, LCDStride)
+
+// LED handles for the ev3 brick.
+var (
+	GreenLeft  = &ev3dev.LED{Name: led{color: "green", side: "left"}}
+	GreenRight = &ev3dev.LED{Name: led{color: "green", side: "right"}}
+	RedLeft    = &ev3dev.LED{Name: led{color: "red", side: "left"}}
+	RedRight   = &ev3dev.LED{Name: led{color: "red", side: "right"}}
+)
+
+// Speaker is a handle to the ev3 speaker.
+var Speaker = ev3dev.NewSpeaker("/dev/input/by-path/platform-snd-legoev3-event")
+
+func init() {
+	ev3dev.RegisterHost("ev3", ev3dev.Host{
+		LED: map[string]*ev3dev.LED{
+			"green-left":  GreenLeft,
+			"green-right": GreenRight,
+			"red-left":    RedLeft,
+			"red-right":   RedRight,
+		},
+		LCD:     LCD,
+		Speaker: Speaker,
+	})
+}
+
+// led is a fmt.Stringer LED name.
+type led struct {
+	color string
+	side  string
+}
+
+func (l led) String() string {
+	var id int
+	switch l.side {
+	case "left":
+		id = 0
+	case "right":
+		id = 1
+	default:
+		panic("ev3: invalid LED side")
+	}
+	return fmt.Sprintf("led%d:%s:brick-status", id, l.color)
+}
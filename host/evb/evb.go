@@ -0,0 +1,54 @@
+// Copyright ©2016 The ev3go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package evb registers the LED handles for the FatcatLab EVB
+// with ev3dev.
+//
+// The EVB has no LCD and no beeper, so LCD and Speaker are left
+// unregistered.
+package evb
+
+import (
+	"fmt"
+
+	"github.com/ev3go/ev3/ev3dev"
+)
+
+// LED handles for the EVB.
+var (
+	GreenLeft  = &ev3dev.LED{Name: led{color: "green", side: "left"}}
+	GreenRight = &ev3dev.LED{Name: led{color: "green", side: "right"}}
+	RedLeft    = &ev3dev.LED{Name: led{color: "red", side: "left"}}
+	RedRight   = &ev3dev.LED{Name: led{color: "red", side: "right"}}
+)
+
+// led is a fmt.Stringer LED name.
+type led struct {
+	color string
+	side  string
+}
+
+func (l led) String() string {
+	var id int
+	switch l.side {
+	case "left":
+		id = 0
+	case "right":
+		id = 1
+	default:
+		panic("evb: invalid LED side")
+	}
+	return fmt.Sprintf("evb-ports:led%d:%s", id, l.color)
+}
+
+func init() {
+	ev3dev.RegisterHost("evb", ev3dev.Host{
+		LED: map[string]*ev3dev.LED{
+			"green-left":  GreenLeft,
+			"green-right": GreenRight,
+			"red-left":    RedLeft,
+			"red-right":   RedRight,
+		},
+	})
+}
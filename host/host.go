@@ -0,0 +1,64 @@
+// Copyright ©2016 The ev3go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package host identifies the ev3dev-compatible board the program
+// is running on.
+package host
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"strings"
+)
+
+// boardInfoPath is the sysfs path describing the running board. It
+// is a var rather than a const so that tests can point it at a fake
+// board-info file.
+var boardInfoPath = "/sys/class/board-info/board0/board"
+
+// deviceTreeModelPath is the device tree path describing the
+// running board on platforms without board-info support. It is a
+// var rather than a const so that tests can point it at a fake
+// device tree.
+var deviceTreeModelPath = "/proc/device-tree/model"
+
+// models maps substrings found in the board identification files
+// to the name under which the corresponding host package registers
+// its handles with ev3dev.RegisterHost.
+var models = []struct {
+	substr string
+	name   string
+}{
+	{"EV3", "ev3"},
+	{"BrickPi3", "brickpi3"},
+	{"BrickPi", "brickpi"},
+	{"PiStorms", "pistorms"},
+	{"EVB", "evb"},
+}
+
+// Detect returns the name of the ev3dev host that the program is
+// currently running on, as registered by the corresponding
+// github.com/ev3go/ev3/host/* package. The returned name can be
+// passed to ev3dev.HostByName to retrieve its device handles.
+//
+// Detect does not import any host package itself; callers must
+// blank-import the host packages they want Detect to be able to
+// recognize, or import github.com/ev3go/ev3/host/all to register
+// all of them.
+func Detect() (string, error) {
+	for _, path := range []string{boardInfoPath, deviceTreeModelPath} {
+		b, err := ioutil.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		b = bytes.TrimRight(b, "\x00\n")
+		for _, m := range models {
+			if strings.Contains(string(b), m.substr) {
+				return m.name, nil
+			}
+		}
+	}
+	return "", fmt.Errorf("host: could not identify board from %s or %s", boardInfoPath, deviceTreeModelPath)
+}
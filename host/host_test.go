@@ -0,0 +1,78 @@
+// Copyright ©2017 The ev3go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package host
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// withFakeBoardInfo points boardInfoPath and deviceTreeModelPath at
+// files in a temporary directory containing the given contents (an
+// empty string leaves the corresponding file absent, as if the
+// platform had no such file), and returns a function that restores
+// both paths.
+func withFakeBoardInfo(t *testing.T, boardInfo, deviceTreeModel string) func() {
+	t.Helper()
+
+	dir := t.TempDir()
+	oldBoardInfoPath, oldDeviceTreeModelPath := boardInfoPath, deviceTreeModelPath
+
+	boardInfoPath = filepath.Join(dir, "board")
+	if boardInfo != "" {
+		if err := os.WriteFile(boardInfoPath, []byte(boardInfo), 0644); err != nil {
+			t.Fatalf("failed to write fake board-info file: %v", err)
+		}
+	}
+	deviceTreeModelPath = filepath.Join(dir, "model")
+	if deviceTreeModel != "" {
+		if err := os.WriteFile(deviceTreeModelPath, []byte(deviceTreeModel), 0644); err != nil {
+			t.Fatalf("failed to write fake device tree model file: %v", err)
+		}
+	}
+
+	return func() {
+		boardInfoPath, deviceTreeModelPath = oldBoardInfoPath, oldDeviceTreeModelPath
+	}
+}
+
+func TestDetect(t *testing.T) {
+	for _, test := range []struct {
+		name            string
+		boardInfo       string
+		deviceTreeModel string
+		want            string
+		wantErr         bool
+	}{
+		{name: "ev3", boardInfo: "LEGO MINDSTORMS EV3\n", want: "ev3"},
+		{name: "brickpi3 over board-info", boardInfo: "Dexter Industries BrickPi3\n", want: "brickpi3"},
+		{name: "brickpi3 substring precedence", boardInfo: "BrickPi3\n", want: "brickpi3"},
+		{name: "brickpi without trailing digit", boardInfo: "Dexter Industries BrickPi\n", want: "brickpi"},
+		{name: "pistorms", boardInfo: "mindsensors.com PiStorms\n", want: "pistorms"},
+		{name: "evb", boardInfo: "FatcatLab EVB\n", want: "evb"},
+		{name: "falls back to device tree model", deviceTreeModel: "Raspberry Pi 3 Model B BrickPi3\x00", want: "brickpi3"},
+		{name: "unrecognized board", boardInfo: "some unknown board\n", wantErr: true},
+		{name: "neither file present", wantErr: true},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			defer withFakeBoardInfo(t, test.boardInfo, test.deviceTreeModel)()
+
+			got, err := Detect()
+			if test.wantErr {
+				if err == nil {
+					t.Fatalf("Detect(): expected an error, got name %q", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Detect(): unexpected error: %v", err)
+			}
+			if got != test.want {
+				t.Errorf("Detect(): got %q, want %q", got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,30 @@
+// Copyright ©2016 The ev3go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package pistorms registers the LED handle for the mindsensors.com
+// PiStorms with ev3dev.
+//
+// The PiStorms has no LCD and no beeper, so LCD and Speaker are
+// left unregistered.
+package pistorms
+
+import "github.com/ev3go/ev3/ev3dev"
+
+// RGB is the handle for the PiStorms' single RGB status LED.
+var RGB = &ev3dev.LED{Name: led("")}
+
+// led is a fmt.Stringer LED name.
+type led string
+
+func (l led) String() string {
+	return "pistorms:brick-status"
+}
+
+func init() {
+	ev3dev.RegisterHost("pistorms", ev3dev.Host{
+		LED: map[string]*ev3dev.LED{
+			"rgb": RGB,
+		},
+	})
+}
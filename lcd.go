@@ -4,23 +4,22 @@
 
 package ev3
 
-import (
-	"github.com/ev3go/ev3dev"
-	"github.com/ev3go/ev3dev/fb"
-)
+import hostev3 "github.com/ev3go/ev3/host/ev3"
 
+// These aliases are kept for backward compatibility; new code should
+// use github.com/ev3go/ev3/host/ev3 directly.
 const (
 	// LCDWidth is the width of the LCD screen in pixels.
-	LCDWidth = 178
+	LCDWidth = hostev3.LCDWidth
 
 	// LCDHeight is the height of the LCD screen in pixels.
-	LCDHeight = 128
+	LCDHeight = hostev3.LCDHeight
 
 	// LCDStride is the width of the LCD screen memory in bytes.
-	LCDStride = 712
+	LCDStride = hostev3.LCDStride
 )
 
 // LCD is the draw image used draw directly to the ev3 LCD screen.
 // Drawing operations are safe for concurrent use, but are not atomic
 // beyond the pixel level. It must be initialized before use.
-var LCD = ev3dev.NewFrameBuffer("/dev/fb0", fb.NewXRGBWith, LCDWidth, LCDHeight, LCDStride)
+var LCD = hostev3.LCD
@@ -4,35 +4,15 @@
 
 package ev3
 
-import (
-	"fmt"
-
-	"github.com/ev3go/ev3dev"
-)
+import hostev3 "github.com/ev3go/ev3/host/ev3"
 
 // LED handles for ev3 devices.
+//
+// These aliases are kept for backward compatibility; new code should
+// use github.com/ev3go/ev3/host/ev3 directly.
 var (
-	GreenLeft  = &ev3dev.LED{Name: led{color: "green", side: "left"}}
-	GreenRight = &ev3dev.LED{Name: led{color: "green", side: "right"}}
-	RedLeft    = &ev3dev.LED{Name: led{color: "red", side: "left"}}
-	RedRight   = &ev3dev.LED{Name: led{color: "red", side: "right"}}
+	GreenLeft  = hostev3.GreenLeft
+	GreenRight = hostev3.GreenRight
+	RedLeft    = hostev3.RedLeft
+	RedRight   = hostev3.RedRight
 )
-
-// led is a fmt.Stringer LED name.
-type led struct {
-	color string
-	side  string
-}
-
-func (l led) String() string {
-	var id int
-	switch l.side {
-	case "left":
-		id = 0
-	case "right":
-		id = 1
-	default:
-		panic("ev3: invalid LED side")
-	}
-	return fmt.Sprintf("led%d:%s:brick-status", id, l.color)
-}
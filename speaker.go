@@ -4,7 +4,10 @@
 
 package ev3
 
-import "github.com/ev3go/ev3dev"
+import hostev3 "github.com/ev3go/ev3/host/ev3"
 
 // Speaker is a handle to the ev3 speaker. It must be initialized before use.
-var Speaker = ev3dev.NewSpeaker("/dev/input/by-path/platform-snd-legoev3-event")
+//
+// This alias is kept for backward compatibility; new code should use
+// github.com/ev3go/ev3/host/ev3 directly.
+var Speaker = hostev3.Speaker